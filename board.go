@@ -0,0 +1,80 @@
+package rpiGpio
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Op is a single digital write applied by Board.RunBatch.
+type Op struct {
+	Pin   string
+	Value string
+}
+
+// Board owns a set of pins described by a PinMap, built lazily into
+// Controls on first use and keyed by pin number so any of a pin's aliases
+// resolve to the same Control.
+type Board struct {
+	pins  PinMap
+	cache *ControlCache
+}
+
+// NewBoard creates a Board over pins. opts are applied to every pin's
+// Control the first time that pin is used, e.g. to share a SetSysfsRoot or
+// SetBackend across the whole board.
+func NewBoard(pins PinMap, opts ...func(*Control) error) *Board {
+	return &Board{
+		pins:  pins,
+		cache: NewControlCache(opts...),
+	}
+}
+
+// Pin returns the Control for alias (a pin number or any of its aliases),
+// creating it on first use. opts are (re-)applied to the Control on every
+// call, cached or not, so later calls can still reconfigure it, e.g.
+// change its control type. It rejects a pin that lacks the capability
+// required by the resulting control type, e.g. "pwm" on a pin without
+// CapPWM.
+func (b *Board) Pin(alias string, opts ...func(*Control) error) (*Control, error) {
+	desc, ok := b.pins.find(alias)
+	if !ok {
+		return nil, fmt.Errorf("Invalid pin alias:%v, choose one of :%v", alias, b.pins.numbers())
+	}
+
+	key := strconv.Itoa(desc.Num)
+	ctrl, err := b.cache.Get(key, []func(*Control) error{SetPin(key)}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctrl.ctype == "pwm" && desc.Caps&CapPWM == 0 {
+		return nil, fmt.Errorf("pin %v doesn't support pwm, choose one of :%v", alias, b.pins.numbers())
+	}
+
+	return ctrl, nil
+}
+
+// RunBatch enables every pin touched by ops (if needed) and then writes
+// every value in a single pass, so scripted sequences like stepper drivers
+// or seven-segment displays don't pay per-write goroutine overhead.
+func (b *Board) RunBatch(ops []Op) error {
+	ctrls := make([]*Control, len(ops))
+	for i, op := range ops {
+		ctrl, err := b.Pin(op.Pin, SetType("toggle"))
+		if err != nil {
+			return err
+		}
+		if err := ctrl.enablePin(); err != nil {
+			return fmt.Errorf("couldn't enable pin %v: %v", op.Pin, err)
+		}
+		ctrls[i] = ctrl
+	}
+
+	for i, op := range ops {
+		ctrl := ctrls[i]
+		if err := ctrl.backend().WriteFile(ctrl.pinFile("value"), []byte(op.Value), 0644); err != nil {
+			return fmt.Errorf("couldn't set pin %v: %v", op.Pin, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package rpiGpio
+
+import "testing"
+
+func TestBoardPinReusesAndReappliesOpts(t *testing.T) {
+	b := NewBoard(gpioPins)
+
+	var calls int
+	countOpt := func(c *Control) error {
+		calls++
+		return nil
+	}
+
+	first, err := b.Pin("18", countOpt)
+	if err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+	second, err := b.Pin("GPIO_18", countOpt)
+	if err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+
+	if first != second {
+		t.Fatalf("Pin() = %p, want cached %p for an alias of the same pin", second, first)
+	}
+	if calls != 2 {
+		t.Fatalf("extra opts called %d times, want 2", calls)
+	}
+}
+
+func TestBoardPinRejectsNonPWMPin(t *testing.T) {
+	b := NewBoard(gpioPins)
+
+	if _, err := b.Pin("4", SetType("pwm")); err == nil {
+		t.Fatalf("Pin() error = nil, want an error requesting pwm on a non-PWM pin")
+	}
+}
+
+func TestBoardPinUnknownAlias(t *testing.T) {
+	b := NewBoard(gpioPins)
+
+	if _, err := b.Pin("not-a-pin"); err == nil {
+		t.Fatalf("Pin() error = nil, want an error for an unknown alias")
+	}
+}
+
+func TestBoardRunBatch(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio17"] = nil
+	fs.files["/fake/gpio/gpio17/value"] = []byte("0\n")
+	fs.files["/fake/gpio/gpio27"] = nil
+	fs.files["/fake/gpio/gpio27/value"] = []byte("0\n")
+
+	b := NewBoard(gpioPins, SetSysfsRoot("/fake/gpio/"))
+	for _, pin := range []string{"17", "27"} {
+		ctrl, err := b.Pin(pin)
+		if err != nil {
+			t.Fatalf("Pin(%v) error = %v", pin, err)
+		}
+		ctrl.fs = fs
+	}
+
+	ops := []Op{{Pin: "17", Value: "1"}, {Pin: "27", Value: "1"}}
+	if err := b.RunBatch(ops); err != nil {
+		t.Fatalf("RunBatch() error = %v", err)
+	}
+
+	for _, pin := range []string{"17", "27"} {
+		got, err := fs.ReadFile("/fake/gpio/gpio" + pin + "/value")
+		if err != nil {
+			t.Fatalf("ReadFile(%v) error = %v", pin, err)
+		}
+		if string(got) != "1" {
+			t.Fatalf("pin %v value = %q, want %q", pin, got, "1")
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package rpiGpio
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// gpiochip0 is the default character-device this driver talks to; the repo
+// targets a single-board Pi, so unlike the pin number there's no need to
+// make the chip itself configurable yet.
+const gpiochip0 = "/dev/gpiochip0"
+
+// the ioctl request numbers and struct layouts below mirror
+// include/uapi/linux/gpio.h from the Linux kernel.
+const (
+	gpioGetLinehandleIOCTL           = 0xC16CB403
+	gpioGetLineeventIOCTL            = 0xC030B404
+	gpiohandleGetLineValuesIOCTL     = 0xC040B408
+	gpiohandleSetLineValuesIOCTL     = 0xC040B409
+	gpiohandlesMax                   = 64
+	gpioMaxName                      = 32
+	gpiohandleRequestInput       int = 1 << 0
+	gpiohandleRequestOutput      int = 1 << 1
+	gpioeventRequestRisingEdge   int = 1 << 0
+	gpioeventRequestFallingEdge  int = 1 << 1
+	gpioeventRequestBothEdges        = gpioeventRequestRisingEdge | gpioeventRequestFallingEdge
+)
+
+type gpiohandleRequest struct {
+	lineOffsets   [gpiohandlesMax]uint32
+	flags         uint32
+	defaultValues [gpiohandlesMax]uint8
+	consumerLabel [gpioMaxName]byte
+	lines         uint32
+	fd            int32
+}
+
+type gpiohandleData struct {
+	values [gpiohandlesMax]uint8
+}
+
+type gpioeventRequest struct {
+	lineOffset    uint32
+	handleFlags   uint32
+	eventFlags    uint32
+	consumerLabel [gpioMaxName]byte
+	fd            int32
+}
+
+func ioctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// cdevLine is a requested gpiohandle, kept open for the lifetime of the
+// Control so repeated Toggle/StartTimer calls don't re-request it.
+type cdevLine struct {
+	fd     int
+	offset uint32
+}
+
+func (l *cdevLine) SetValue(v uint8) error {
+	data := gpiohandleData{}
+	data.values[0] = v
+	return ioctl(uintptr(l.fd), gpiohandleSetLineValuesIOCTL, unsafe.Pointer(&data))
+}
+
+func (l *cdevLine) GetValue() (uint8, error) {
+	var data gpiohandleData
+	if err := ioctl(uintptr(l.fd), gpiohandleGetLineValuesIOCTL, unsafe.Pointer(&data)); err != nil {
+		return 0, err
+	}
+	return data.values[0], nil
+}
+
+func (l *cdevLine) Close() error {
+	return syscall.Close(l.fd)
+}
+
+func (c *Control) gpiochipPath() string {
+	return gpiochip0
+}
+
+// cdevOpenLine requests and caches a line handle for c.pin, matching the
+// direction configured via SetDirection.
+func (c *Control) cdevOpenLine() (*cdevLine, error) {
+	if c.line != nil {
+		return c.line, nil
+	}
+
+	offset, err := strconv.Atoi(c.pin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pin for cdev backend:%v", c.pin)
+	}
+
+	chipFd, err := syscall.Open(c.gpiochipPath(), syscall.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open %v: %v", c.gpiochipPath(), err)
+	}
+	defer syscall.Close(chipFd)
+
+	flags := uint32(gpiohandleRequestOutput)
+	if c.direction == "in" {
+		flags = uint32(gpiohandleRequestInput)
+	}
+
+	req := gpiohandleRequest{flags: flags, lines: 1}
+	req.lineOffsets[0] = uint32(offset)
+	copy(req.consumerLabel[:], "rpiGpio")
+
+	if err := ioctl(uintptr(chipFd), gpioGetLinehandleIOCTL, unsafe.Pointer(&req)); err != nil {
+		return nil, fmt.Errorf("couldn't request line handle for pin %v: %v", c.pin, err)
+	}
+
+	c.line = &cdevLine{fd: int(req.fd), offset: uint32(offset)}
+	return c.line, nil
+}
+
+func (c *Control) cdevStartTimer() error {
+	line, err := c.cdevOpenLine()
+	if err != nil {
+		log.Printf("I couldn't enable pin %v, because %v", c.pin, err)
+		return err
+	}
+	if err := line.SetValue(1); err != nil {
+		return err
+	}
+	go func() {
+		time.Sleep(c.delay)
+		if err := line.SetValue(0); err != nil {
+			log.Printf("Couldn't disable pin:%v error:%v", c.pin, err)
+		}
+	}()
+	return nil
+}
+
+func (c *Control) cdevValue() (uint8, error) {
+	line, err := c.cdevOpenLine()
+	if err != nil {
+		return 0, err
+	}
+	return line.GetValue()
+}
+
+func (c *Control) cdevToggle() error {
+	line, err := c.cdevOpenLine()
+	if err != nil {
+		log.Printf("I couldn't enable pin %v, because %v", c.pin, err)
+		return err
+	}
+
+	v, err := line.GetValue()
+	if err != nil {
+		log.Printf("Oh boy can't read the status of pin	%v becasue I don't have my glasses and %v", c.pin, err)
+	}
+
+	if v == 1 {
+		return line.SetValue(0)
+	}
+	return line.SetValue(1)
+}
+
+// cdevWatch requests a line event fd for the pin and registers it with the
+// shared epoll instance like the sysfs Watch implementation does, but as
+// epollKindQueue so every queued gpioevent_data record is drained.
+func (c *Control) cdevWatch(edge string, cb func(pin string)) error {
+	var eventFlags uint32
+	switch edge {
+	case "rising":
+		eventFlags = uint32(gpioeventRequestRisingEdge)
+	case "falling":
+		eventFlags = uint32(gpioeventRequestFallingEdge)
+	case "both":
+		eventFlags = uint32(gpioeventRequestBothEdges)
+	case "none":
+		return c.StopWatch()
+	}
+
+	offset, err := strconv.Atoi(c.pin)
+	if err != nil {
+		return fmt.Errorf("invalid pin for cdev backend:%v", c.pin)
+	}
+
+	chipFd, err := syscall.Open(c.gpiochipPath(), syscall.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("couldn't open %v: %v", c.gpiochipPath(), err)
+	}
+	defer syscall.Close(chipFd)
+
+	req := gpioeventRequest{
+		lineOffset:  uint32(offset),
+		handleFlags: uint32(gpiohandleRequestInput),
+		eventFlags:  eventFlags,
+	}
+	copy(req.consumerLabel[:], "rpiGpio")
+
+	if err := ioctl(uintptr(chipFd), gpioGetLineeventIOCTL, unsafe.Pointer(&req)); err != nil {
+		return fmt.Errorf("couldn't request line event for pin %v: %v", c.pin, err)
+	}
+
+	// the drain loop in gpioEpoll.loop reads this fd until EAGAIN, so it
+	// must be non-blocking or a quiet fd would hang the epoll goroutine.
+	if err := syscall.SetNonblock(int(req.fd), true); err != nil {
+		syscall.Close(int(req.fd))
+		return fmt.Errorf("couldn't set line event fd non-blocking for pin %v: %v", c.pin, err)
+	}
+
+	f := os.NewFile(uintptr(req.fd), "gpioevent")
+	e, err := getEpoller()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := e.add(f, c.pin, cb, epollKindQueue); err != nil {
+		f.Close()
+		return err
+	}
+	c.watchFile = f
+	return nil
+}
@@ -0,0 +1,52 @@
+package rpiGpio
+
+import "sync"
+
+// ControlCache lazily builds and caches one Control per key, re-applying a
+// caller-supplied set of options on every lookup. It backs both Board.Pin
+// and server.Server's per-pin sessions, which both need the same
+// lazy-create-then-reconfigure behaviour: create once, then keep reusing the
+// same Control so later calls reconfigure it instead of silently losing
+// whatever options an earlier call set.
+type ControlCache struct {
+	mu   sync.Mutex
+	ctrl map[string]*Control
+	opts []func(*Control) error
+}
+
+// NewControlCache creates a ControlCache. opts are applied to every Control
+// the first time its key is used, e.g. to share a SetSysfsRoot or
+// SetBackend across every cached Control.
+func NewControlCache(opts ...func(*Control) error) *ControlCache {
+	return &ControlCache{
+		ctrl: make(map[string]*Control),
+		opts: opts,
+	}
+}
+
+// Get returns the Control for key, creating it with newOpts (typically
+// including SetPin) on first use. extra is (re-)applied on every call,
+// cached or not, so a later call can still reconfigure the Control instead
+// of running against stale settings from whichever call created it.
+func (cc *ControlCache) Get(key string, newOpts []func(*Control) error, extra ...func(*Control) error) (*Control, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	ctrl, ok := cc.ctrl[key]
+	if !ok {
+		allOpts := append(append([]func(*Control) error{}, newOpts...), cc.opts...)
+		created, err := NewControl(allOpts...)
+		if err != nil {
+			return nil, err
+		}
+		ctrl = created
+		cc.ctrl[key] = ctrl
+	}
+
+	for _, o := range extra {
+		if err := o(ctrl); err != nil {
+			return nil, err
+		}
+	}
+	return ctrl, nil
+}
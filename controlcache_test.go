@@ -0,0 +1,47 @@
+package rpiGpio
+
+import "testing"
+
+func TestControlCacheReusesAndReappliesOpts(t *testing.T) {
+	cc := NewControlCache()
+	newOpts := []func(*Control) error{SetPin("18")}
+
+	var calls int
+	countOpt := func(c *Control) error {
+		calls++
+		return nil
+	}
+
+	first, err := cc.Get("18", newOpts, countOpt)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	second, err := cc.Get("18", newOpts, countOpt)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if first != second {
+		t.Fatalf("Get() = %p, want cached %p", second, first)
+	}
+	if calls != 2 {
+		t.Fatalf("extra opts called %d times, want 2", calls)
+	}
+}
+
+func TestControlCacheDistinctKeys(t *testing.T) {
+	cc := NewControlCache()
+
+	a, err := cc.Get("18", []func(*Control) error{SetPin("18")})
+	if err != nil {
+		t.Fatalf("Get(18) error = %v", err)
+	}
+	b, err := cc.Get("27", []func(*Control) error{SetPin("27")})
+	if err != nil {
+		t.Fatalf("Get(27) error = %v", err)
+	}
+
+	if a == b {
+		t.Fatalf("Get() returned the same Control for different keys")
+	}
+}
@@ -0,0 +1,30 @@
+package rpiGpio
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+// fsBackend abstracts the filesystem calls Control makes against sysfs so
+// tests (and non-sysfs targets) can swap in a fake implementation via
+// SetSysfsRoot/fs rather than hitting the real /sys/class/gpio/ tree.
+type fsBackend interface {
+	Stat(name string) (os.FileInfo, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	ReadFile(name string) ([]byte, error)
+}
+
+// sysfsBackend is the default fsBackend, backed by the real filesystem.
+type sysfsBackend struct{}
+
+func (sysfsBackend) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (sysfsBackend) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(name, data, perm)
+}
+
+func (sysfsBackend) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
@@ -0,0 +1,121 @@
+package rpiGpio
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFS is an in-memory fsBackend used by tests so they don't touch the
+// real /sys/class/gpio/ tree. It's shared between the test goroutine and
+// the background goroutine startTimer spawns, so access is guarded by mu.
+type fakeFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newFakeFS() *fakeFS {
+	return &fakeFS{files: make(map[string][]byte)}
+}
+
+func (f *fakeFS) Stat(name string) (os.FileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.files[name]; ok {
+		return nil, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (f *fakeFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[name] = cp
+	return nil
+}
+
+func (f *fakeFS) ReadFile(name string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.files[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return d, nil
+}
+
+func newTestControl(t *testing.T, fs *fakeFS, opts ...func(*Control) error) *Control {
+	t.Helper()
+	allOpts := append([]func(*Control) error{
+		SetPin("18"),
+		SetSysfsRoot("/fake/gpio/"),
+	}, opts...)
+	ctrl, err := NewControl(allOpts...)
+	if err != nil {
+		t.Fatalf("NewControl() error = %v", err)
+	}
+	ctrl.fs = fs
+	return ctrl
+}
+
+func TestToggleUsesFakeBackend(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio18"] = nil
+	fs.files["/fake/gpio/gpio18/value"] = []byte("0\n")
+
+	ctrl := newTestControl(t, fs, SetType("toggle"))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := fs.ReadFile("/fake/gpio/gpio18/value")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != "1" {
+		t.Fatalf("toggle() value = %q, want %q", got, "1")
+	}
+}
+
+func TestStartTimerUsesFakeBackend(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio18"] = nil
+
+	ctrl := newTestControl(t, fs, SetType("timer"), SetDelay("10ms"))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := fs.ReadFile("/fake/gpio/gpio18/value")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != "1" {
+		t.Fatalf("startTimer() value = %q, want %q", got, "1")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, _ = fs.ReadFile("/fake/gpio/gpio18/value")
+		if string(got) == "0" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("startTimer() after delay value = %q, want %q", got, "0")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSetSysfsRootDefault(t *testing.T) {
+	ctrl, err := NewControl(SetPin("18"), SetSysfsRoot(""))
+	if err != nil {
+		t.Fatalf("NewControl() error = %v", err)
+	}
+	if ctrl.sysfsRoot != sysfs {
+		t.Fatalf("sysfsRoot = %q, want %q", ctrl.sysfsRoot, sysfs)
+	}
+}
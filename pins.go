@@ -0,0 +1,90 @@
+package rpiGpio
+
+import "strconv"
+
+// Caps describes what a GPIO pin can be used for, mirroring the
+// capability-bitmask idea used by board-abstraction libraries like embd.
+type Caps uint
+
+const (
+	CapDigital Caps = 1 << iota
+	CapPWM
+	CapI2C
+	CapSPI
+	CapUART
+)
+
+// PinDesc describes a single GPIO pin: its number, any aliases it's known
+// by (its physical header position or function name), and what it
+// supports.
+type PinDesc struct {
+	Num     int
+	Aliases []string
+	Caps    Caps
+}
+
+// PinMap is an ordered set of PinDesc, used to build a Board.
+type PinMap []PinDesc
+
+// gpioPins is the PinMap this driver knows about for the standard 40-pin
+// Pi header. GPIO 12, 13, 18 and 19 additionally expose hardware PWM;
+// 2/3, 7/8/9/10/11 and 14/15 expose I2C, SPI and UART respectively.
+var gpioPins = PinMap{
+	{Num: 2, Aliases: []string{"P1_03", "GPIO_2", "SDA1"}, Caps: CapDigital | CapI2C},
+	{Num: 3, Aliases: []string{"P1_05", "GPIO_3", "SCL1"}, Caps: CapDigital | CapI2C},
+	{Num: 4, Aliases: []string{"P1_07", "GPIO_4"}, Caps: CapDigital},
+	{Num: 5, Aliases: []string{"P1_29", "GPIO_5"}, Caps: CapDigital},
+	{Num: 6, Aliases: []string{"P1_31", "GPIO_6"}, Caps: CapDigital},
+	{Num: 7, Aliases: []string{"P1_26", "GPIO_7", "SPI0_CE1"}, Caps: CapDigital | CapSPI},
+	{Num: 8, Aliases: []string{"P1_24", "GPIO_8", "SPI0_CE0"}, Caps: CapDigital | CapSPI},
+	{Num: 9, Aliases: []string{"P1_21", "GPIO_9", "SPI0_MISO"}, Caps: CapDigital | CapSPI},
+	{Num: 10, Aliases: []string{"P1_19", "GPIO_10", "SPI0_MOSI"}, Caps: CapDigital | CapSPI},
+	{Num: 11, Aliases: []string{"P1_23", "GPIO_11", "SPI0_SCLK"}, Caps: CapDigital | CapSPI},
+	{Num: 12, Aliases: []string{"P1_32", "GPIO_12"}, Caps: CapDigital | CapPWM},
+	{Num: 13, Aliases: []string{"P1_33", "GPIO_13"}, Caps: CapDigital | CapPWM},
+	{Num: 14, Aliases: []string{"P1_08", "GPIO_14", "UART_TXD"}, Caps: CapDigital | CapUART},
+	{Num: 15, Aliases: []string{"P1_10", "GPIO_15", "UART_RXD"}, Caps: CapDigital | CapUART},
+	{Num: 16, Aliases: []string{"P1_36", "GPIO_16"}, Caps: CapDigital},
+	{Num: 17, Aliases: []string{"P1_11", "GPIO_17"}, Caps: CapDigital},
+	{Num: 18, Aliases: []string{"P1_12", "GPIO_18", "PCM_CLK"}, Caps: CapDigital | CapPWM},
+	{Num: 19, Aliases: []string{"P1_35", "GPIO_19"}, Caps: CapDigital | CapPWM},
+	{Num: 20, Aliases: []string{"P1_38", "GPIO_20"}, Caps: CapDigital},
+	{Num: 22, Aliases: []string{"P1_15", "GPIO_22"}, Caps: CapDigital},
+	{Num: 23, Aliases: []string{"P1_16", "GPIO_23"}, Caps: CapDigital},
+	{Num: 24, Aliases: []string{"P1_18", "GPIO_24"}, Caps: CapDigital},
+	{Num: 25, Aliases: []string{"P1_22", "GPIO_25"}, Caps: CapDigital},
+	{Num: 26, Aliases: []string{"P1_37", "GPIO_26"}, Caps: CapDigital},
+	{Num: 27, Aliases: []string{"P1_13", "GPIO_27"}, Caps: CapDigital},
+}
+
+// find looks up a PinDesc by pin number (as a string) or by any of its
+// aliases.
+func (m PinMap) find(d string) (*PinDesc, bool) {
+	for i := range m {
+		if strconv.Itoa(m[i].Num) == d {
+			return &m[i], true
+		}
+		for _, a := range m[i].Aliases {
+			if a == d {
+				return &m[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (m PinMap) numbers() []int {
+	nums := make([]int, len(m))
+	for i, p := range m {
+		nums[i] = p.Num
+	}
+	return nums
+}
+
+func findPin(d string) (*PinDesc, bool) {
+	return gpioPins.find(d)
+}
+
+func pinNumbers() []int {
+	return gpioPins.numbers()
+}
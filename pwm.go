@@ -0,0 +1,169 @@
+package rpiGpio
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const DefaultPWMFrequency = 1000.0
+const DefaultPWMDutyCycle = 50.0
+
+const pwmSysfs = "/sys/class/pwm/pwmchip0/"
+
+// hwPWMChannel maps the pins that expose hardware PWM on the Pi to their
+// pwmchip0 channel number.
+var hwPWMChannel = map[string]int{
+	"12": 0,
+	"18": 0,
+	"13": 1,
+	"19": 1,
+}
+
+// SetPWMFrequency sets the PWM frequency in Hz, used by both the hardware
+// and software PWM implementations. Leaving it unset defaults to
+// DefaultPWMFrequency.
+func SetPWMFrequency(hz float64) func(*Control) error {
+	return func(c *Control) error {
+		if hz <= 0 {
+			return fmt.Errorf("Invalid PWM frequency:%v, must be greater than 0", hz)
+		}
+		c.pwmFreq = &hz
+		return nil
+	}
+}
+
+// SetPWMDutyCycle sets the PWM duty cycle as a percentage (0-100), where 0
+// is fully off and 100 is fully on. Leaving it unset defaults to
+// DefaultPWMDutyCycle.
+func SetPWMDutyCycle(percent float64) func(*Control) error {
+	return func(c *Control) error {
+		if percent < 0 || percent > 100 {
+			return fmt.Errorf("Invalid PWM duty cycle:%v, must be between 0 and 100", percent)
+		}
+		c.pwmDuty = &percent
+		return nil
+	}
+}
+
+// pwmFrequency returns the configured PWM frequency, or DefaultPWMFrequency
+// if SetPWMFrequency was never called.
+func (c *Control) pwmFrequency() float64 {
+	if c.pwmFreq != nil {
+		return *c.pwmFreq
+	}
+	return DefaultPWMFrequency
+}
+
+// pwmDutyCycle returns the configured PWM duty cycle, or
+// DefaultPWMDutyCycle if SetPWMDutyCycle was never called.
+func (c *Control) pwmDutyCycle() float64 {
+	if c.pwmDuty != nil {
+		return *c.pwmDuty
+	}
+	return DefaultPWMDutyCycle
+}
+
+func (c *Control) startPWM() error {
+	// Run can be called again on a live Control to pick up new frequency/duty
+	// settings (e.g. ControlCache/Board.Pin reapplying options), so stop
+	// whatever PWM session is already running before starting the new one,
+	// or the old software PWM goroutine would leak with no way to reach it.
+	if err := c.Stop(); err != nil {
+		log.Printf("Couldn't stop previous PWM session for pin %v: %v", c.pin, err)
+	}
+
+	if desc, ok := findPin(c.pin); ok && desc.Caps&CapPWM != 0 {
+		return c.startHardwarePWM()
+	}
+	return c.startSoftwarePWM()
+}
+
+func (c *Control) startHardwarePWM() error {
+	ch, ok := hwPWMChannel[c.pin]
+	if !ok {
+		return fmt.Errorf("pin %v has no hardware PWM channel mapping", c.pin)
+	}
+	chanDir := pwmSysfs + "pwm" + strconv.Itoa(ch)
+
+	if _, err := c.backend().Stat(chanDir); os.IsNotExist(err) {
+		if err := c.backend().WriteFile(pwmSysfs+"export", []byte(strconv.Itoa(ch)), 0644); err != nil {
+			return fmt.Errorf("couldn't export pwm channel %v: %v", ch, err)
+		}
+	}
+
+	period := int64(1e9 / c.pwmFrequency())
+	duty := int64(float64(period) * c.pwmDutyCycle() / 100)
+
+	if err := c.backend().WriteFile(chanDir+"/period", []byte(strconv.FormatInt(period, 10)), 0644); err != nil {
+		return fmt.Errorf("couldn't set period for pwm channel %v: %v", ch, err)
+	}
+	if err := c.backend().WriteFile(chanDir+"/duty_cycle", []byte(strconv.FormatInt(duty, 10)), 0644); err != nil {
+		return fmt.Errorf("couldn't set duty_cycle for pwm channel %v: %v", ch, err)
+	}
+	if err := c.backend().WriteFile(chanDir+"/enable", []byte("1"), 0644); err != nil {
+		return fmt.Errorf("couldn't enable pwm channel %v: %v", ch, err)
+	}
+
+	c.pwmChanDir = chanDir
+	return nil
+}
+
+// softwarePWMDurations computes the on/off portions of one software PWM
+// cycle for the given frequency (Hz) and duty cycle (0-100).
+func softwarePWMDurations(freqHz, dutyPercent float64) (on, off time.Duration) {
+	period := time.Duration(float64(time.Second) / freqHz)
+	on = time.Duration(float64(period) * dutyPercent / 100)
+	return on, period - on
+}
+
+func (c *Control) startSoftwarePWM() error {
+	if err := c.enablePin(); err != nil {
+		return fmt.Errorf("couldn't enable pin %v, because %v", c.pin, err)
+	}
+
+	on, off := softwarePWMDurations(c.pwmFrequency(), c.pwmDutyCycle())
+
+	stop := make(chan struct{})
+	c.pwmStop = stop
+
+	go func() {
+		for {
+			if on > 0 {
+				c.backend().WriteFile(c.pinFile("value"), []byte("1"), 0644)
+				select {
+				case <-stop:
+					return
+				case <-time.After(on):
+				}
+			}
+			if off > 0 {
+				c.backend().WriteFile(c.pinFile("value"), []byte("0"), 0644)
+				select {
+				case <-stop:
+					return
+				case <-time.After(off):
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop cancels a running software PWM goroutine, or disables the hardware
+// PWM channel if one was configured.
+func (c *Control) Stop() error {
+	if c.pwmStop != nil {
+		close(c.pwmStop)
+		c.pwmStop = nil
+	}
+
+	if c.pwmChanDir != "" {
+		err := c.backend().WriteFile(c.pwmChanDir+"/enable", []byte("0"), 0644)
+		c.pwmChanDir = ""
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package rpiGpio
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSetPWMDutyCycleZeroIsNotDefaulted(t *testing.T) {
+	ctrl, err := NewControl(SetPin("18"), SetPWMDutyCycle(0))
+	if err != nil {
+		t.Fatalf("NewControl() error = %v", err)
+	}
+	if got := ctrl.pwmDutyCycle(); got != 0 {
+		t.Fatalf("pwmDutyCycle() = %v, want 0", got)
+	}
+}
+
+func TestPWMDutyCycleDefaultsWhenUnset(t *testing.T) {
+	ctrl, err := NewControl(SetPin("18"))
+	if err != nil {
+		t.Fatalf("NewControl() error = %v", err)
+	}
+	if got := ctrl.pwmDutyCycle(); got != DefaultPWMDutyCycle {
+		t.Fatalf("pwmDutyCycle() = %v, want %v", got, DefaultPWMDutyCycle)
+	}
+}
+
+func TestSetPWMFrequencyRejectsZero(t *testing.T) {
+	if _, err := NewControl(SetPin("18"), SetPWMFrequency(0)); err == nil {
+		t.Fatalf("NewControl() error = nil, want an error for a zero frequency")
+	}
+}
+
+func TestSoftwarePWMDurations(t *testing.T) {
+	on, off := softwarePWMDurations(1000, 25)
+	wantPeriod := float64(1e6) // 1000Hz -> 1ms period, in nanoseconds
+	if got := float64(on + off); got != wantPeriod {
+		t.Fatalf("on+off = %v, want period %v", got, wantPeriod)
+	}
+	if got := float64(on); got != wantPeriod*0.25 {
+		t.Fatalf("on = %v, want %v (25%% of period)", got, wantPeriod*0.25)
+	}
+}
+
+func TestStartHardwarePWMWritesPeriodAndDuty(t *testing.T) {
+	fs := newFakeFS()
+	fs.files[pwmSysfs+"pwm0"] = nil // already exported
+
+	ctrl := newTestControl(t, fs, SetPin("18"), SetType("pwm"), SetPWMFrequency(1000), SetPWMDutyCycle(25))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantPeriod := strconv.FormatInt(int64(1e9/1000), 10)
+	if got, err := fs.ReadFile(pwmSysfs + "pwm0/period"); err != nil || string(got) != wantPeriod {
+		t.Fatalf("period = %q, err = %v, want %q", got, err, wantPeriod)
+	}
+
+	wantDuty := strconv.FormatInt(int64(1e9/1000*25/100), 10)
+	if got, err := fs.ReadFile(pwmSysfs + "pwm0/duty_cycle"); err != nil || string(got) != wantDuty {
+		t.Fatalf("duty_cycle = %q, err = %v, want %q", got, err, wantDuty)
+	}
+
+	if got, err := fs.ReadFile(pwmSysfs + "pwm0/enable"); err != nil || string(got) != "1" {
+		t.Fatalf("enable = %q, err = %v, want %q", got, err, "1")
+	}
+}
+
+func TestStartHardwarePWMExportsWhenMissing(t *testing.T) {
+	fs := newFakeFS()
+
+	ctrl := newTestControl(t, fs, SetPin("18"), SetType("pwm"))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, err := fs.ReadFile(pwmSysfs + "export"); err != nil || string(got) != "0" {
+		t.Fatalf("export = %q, err = %v, want channel %q", got, err, "0")
+	}
+}
+
+func TestStopDisablesHardwarePWM(t *testing.T) {
+	fs := newFakeFS()
+	fs.files[pwmSysfs+"pwm0"] = nil
+
+	ctrl := newTestControl(t, fs, SetPin("18"), SetType("pwm"))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := ctrl.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if got, err := fs.ReadFile(pwmSysfs + "pwm0/enable"); err != nil || string(got) != "0" {
+		t.Fatalf("enable = %q, err = %v, want %q after Stop()", got, err, "0")
+	}
+}
+
+// TestStartPWMStopsPreviousSoftwarePWM locks in that a second Run() on a
+// Control already running software PWM stops the first goroutine instead of
+// leaking it with no way to reach it.
+func TestStartPWMStopsPreviousSoftwarePWM(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio4"] = nil
+	fs.files["/fake/gpio/gpio4/value"] = []byte("0\n")
+
+	ctrl := newTestControl(t, fs, SetPin("4"), SetType("pwm"), SetPWMFrequency(1000), SetPWMDutyCycle(50))
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	first := ctrl.pwmStop
+	if first == nil {
+		t.Fatalf("pwmStop = nil after first Run()")
+	}
+
+	if err := SetPWMDutyCycle(75)(ctrl); err != nil {
+		t.Fatalf("SetPWMDutyCycle() error = %v", err)
+	}
+	if err := ctrl.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	second := ctrl.pwmStop
+	if second == nil || second == first {
+		t.Fatalf("pwmStop didn't change across Run() calls")
+	}
+
+	select {
+	case _, ok := <-first:
+		if ok {
+			t.Fatalf("first pwmStop channel received a value instead of being closed")
+		}
+	default:
+		t.Fatalf("first pwmStop channel wasn't closed by the second Run()")
+	}
+
+	if err := ctrl.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
@@ -3,36 +3,35 @@ package rpiGpio
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-var (
-	gpioPins = []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 22, 23, 24, 25, 26, 27}
-)
-
 const sysfs string = "/sys/class/gpio/"
-const sysfsGPIOenable string = sysfs + "export"
-const sysfsGPIOdisable string = sysfs + "unexport"
 
 const DefaultDelay = 2
 const DefaultPin = "18"
 const DefaultType = "timer"
+const DefaultDirection = "out"
+const DefaultBackend = "sysfs"
 
 // Controller interface describes the main funcitons when triggering a pin
 type Controller interface {
 	StartTimer(ch chan string) error
 	Toggle(ch chan string) error
+	Watch(edge string, cb func(pin string)) error
 }
 
-//NewControl the constructor with some defaults
+// NewControl the constructor with some defaults
 func NewControl(opts ...func(*Control) error) (*Control, error) {
-	ctrl := &Control{}
+	ctrl := &Control{
+		sysfsRoot:   sysfs,
+		fs:          sysfsBackend{},
+		backendType: DefaultBackend,
+	}
 	for _, o := range opts {
 		if err := o(ctrl); err != nil {
 			return nil, err
@@ -44,9 +43,19 @@ func NewControl(opts ...func(*Control) error) (*Control, error) {
 
 // Control holds all configuration
 type Control struct {
-	ctype string
-	pin   string
-	delay time.Duration
+	ctype       string
+	pin         string
+	delay       time.Duration
+	direction   string
+	watchFile   *os.File
+	sysfsRoot   string
+	fs          fsBackend
+	backendType string
+	line        *cdevLine
+	pwmFreq     *float64
+	pwmDuty     *float64
+	pwmStop     chan struct{}
+	pwmChanDir  string
 }
 
 // SetType is the controller ctype setter
@@ -55,7 +64,7 @@ func SetType(d string) func(*Control) error {
 		switch strings.TrimSpace(d) {
 		case "":
 			c.ctype = DefaultType
-		case "timer", "toggle":
+		case "timer", "toggle", "pwm":
 			c.ctype = strings.TrimSpace(d)
 		default:
 			return errors.New("Invalid control type:" + d)
@@ -64,21 +73,19 @@ func SetType(d string) func(*Control) error {
 	}
 }
 
-//SetPin the pin on gpio that willbe controlled
+// SetPin the pin on gpio that willbe controlled, by number or by alias
+// (e.g. "P1_12", "GPIO_18", "PCM_CLK")
 func SetPin(d string) func(*Control) error {
 	return func(c *Control) error {
 		if d == "" {
 			c.pin = DefaultPin
 			return nil
 		}
-		for _, v := range gpioPins {
-			if strconv.Itoa(v) == d {
-				c.pin = d
-				return nil
-			}
+		if desc, ok := findPin(d); ok {
+			c.pin = strconv.Itoa(desc.Num)
+			return nil
 		}
-		sort.Ints(gpioPins)
-		return fmt.Errorf("Invalid GPIO pin number:%v, choose one of :%v", d, gpioPins)
+		return fmt.Errorf("Invalid GPIO pin number:%v, choose one of :%v", d, pinNumbers())
 	}
 }
 
@@ -97,16 +104,84 @@ func SetDelay(d string) func(*Control) error {
 	}
 }
 
+// SetDirection sets the pin direction, "in" or "out". Watching a pin for
+// edge events requires "in"; everything else defaults to "out".
+func SetDirection(d string) func(*Control) error {
+	return func(c *Control) error {
+		switch strings.TrimSpace(d) {
+		case "":
+			c.direction = DefaultDirection
+		case "in", "out":
+			c.direction = strings.TrimSpace(d)
+		default:
+			return fmt.Errorf("Invalid pin direction:%v, choose one of :[in out]", d)
+		}
+		return nil
+	}
+}
+
+// SetBackend selects how Control talks to the kernel: "sysfs" (default)
+// uses the deprecated /sys/class/gpio/ tree, "cdev" uses the modern
+// /dev/gpiochipN character-device ioctl ABI.
+func SetBackend(d string) func(*Control) error {
+	return func(c *Control) error {
+		switch strings.TrimSpace(d) {
+		case "":
+			c.backendType = DefaultBackend
+		case "sysfs", "cdev":
+			c.backendType = strings.TrimSpace(d)
+		default:
+			return fmt.Errorf("Invalid backend:%v, choose one of :[sysfs cdev]", d)
+		}
+		return nil
+	}
+}
+
+// SetSysfsRoot overrides the base sysfs GPIO directory (default
+// "/sys/class/gpio/"), useful for testing against a fake filesystem or for
+// targets where sysfs is bind-mounted somewhere else.
+func SetSysfsRoot(path string) func(*Control) error {
+	return func(c *Control) error {
+		if path == "" {
+			c.sysfsRoot = sysfs
+			return nil
+		}
+		if !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+		c.sysfsRoot = path
+		return nil
+	}
+}
+
+func (c *Control) gpioEnablePath() string  { return c.sysfsRoot + "export" }
+func (c *Control) gpioDisablePath() string { return c.sysfsRoot + "unexport" }
+func (c *Control) pinDir() string          { return c.sysfsRoot + "gpio" + c.pin }
+func (c *Control) pinFile(name string) string {
+	return c.pinDir() + "/" + name
+}
+
+func (c *Control) backend() fsBackend {
+	if c.fs == nil {
+		c.fs = sysfsBackend{}
+	}
+	return c.fs
+}
+
 func (c *Control) enablePin() error {
+	if c.direction == "" {
+		c.direction = DefaultDirection
+	}
+
 	// enable if not already enabled
-	if _, err := os.Stat(sysfs + "gpio" + c.pin); os.IsNotExist(err) {
-		if _, err := os.Stat(sysfsGPIOenable); os.IsNotExist(err) {
+	if _, err := c.backend().Stat(c.pinDir()); os.IsNotExist(err) {
+		if _, err := c.backend().Stat(c.gpioEnablePath()); os.IsNotExist(err) {
 			return err
 		}
-		if err := ioutil.WriteFile(sysfsGPIOenable, []byte(c.pin), 0644); err != nil {
+		if err := c.backend().WriteFile(c.gpioEnablePath(), []byte(c.pin), 0644); err != nil {
 			return err
 		}
-		if err := ioutil.WriteFile(sysfs+"gpio"+c.pin+"/direction", []byte("out"), 0644); err != nil {
+		if err := c.backend().WriteFile(c.pinFile("direction"), []byte(c.direction), 0644); err != nil {
 			return err
 		}
 	}
@@ -114,12 +189,12 @@ func (c *Control) enablePin() error {
 }
 
 func (c *Control) disablePin() {
-	if _, err := os.Stat(sysfs + "gpio" + c.pin); os.IsNotExist(err) {
+	if _, err := c.backend().Stat(c.pinDir()); os.IsNotExist(err) {
 		// it is already disabled so nothing else to do, bail out
 		return
 	}
 
-	err := ioutil.WriteFile(sysfsGPIOdisable, []byte(c.pin), 0644)
+	err := c.backend().WriteFile(c.gpioDisablePath(), []byte(c.pin), 0644)
 	if err != nil {
 		log.Printf("Oops can't disable pin %v because %v", c.pin, err)
 	}
@@ -132,45 +207,73 @@ func (c *Control) Run() error {
 		return c.startTimer()
 	case "toggle":
 		return c.toggle()
+	case "pwm":
+		return c.startPWM()
 	default:
 		return fmt.Errorf("Invalid control type:%v", c.ctype)
 	}
 }
 
 func (c *Control) startTimer() error {
+	if c.backendType == "cdev" {
+		return c.cdevStartTimer()
+	}
 	if err := c.enablePin(); err != nil {
 		log.Printf("I couldn't enable pin %v, because %v", c.pin, err)
 		return err
 	}
-	if err := ioutil.WriteFile(sysfs+"gpio"+c.pin+"/value", []byte("1"), 0644); err != nil {
+	if err := c.backend().WriteFile(c.pinFile("value"), []byte("1"), 0644); err != nil {
 		return err
 	}
 	go func() {
 		time.Sleep(c.delay)
-		if err := ioutil.WriteFile(sysfs+"gpio"+c.pin+"/value", []byte("0"), 0644); err != nil {
+		if err := c.backend().WriteFile(c.pinFile("value"), []byte("0"), 0644); err != nil {
 			log.Printf("Couldn't disable pin:%v error:%v", c.pin, err)
 		}
 	}()
 	return nil
 }
 
+// Value returns the pin's current value, "0" or "1".
+func (c *Control) Value() (string, error) {
+	if c.backendType == "cdev" {
+		v, err := c.cdevValue()
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(v)), nil
+	}
+
+	if err := c.enablePin(); err != nil {
+		return "", err
+	}
+	d, err := c.backend().ReadFile(c.pinFile("value"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(d)), nil
+}
+
 func (c *Control) toggle() error {
+	if c.backendType == "cdev" {
+		return c.cdevToggle()
+	}
 	if err := c.enablePin(); err != nil {
 		log.Printf("I couldn't enable pin %v, because %v", c.pin, err)
 	}
 
-	d, err := ioutil.ReadFile(sysfs + "gpio" + c.pin + "/value")
+	d, err := c.backend().ReadFile(c.pinFile("value"))
 	if err != nil {
 		log.Printf("Oh boy can't read the status of pin	%v becasue I don't have my glasses and %v", c.pin, err)
 	}
 
 	if string(d) == "1\n" {
-		if err := ioutil.WriteFile(sysfs+"gpio"+c.pin+"/value", []byte("0"), 0644); err != nil {
+		if err := c.backend().WriteFile(c.pinFile("value"), []byte("0"), 0644); err != nil {
 			return err
 		}
 		return nil
 	}
-	if err := ioutil.WriteFile(sysfs+"gpio"+c.pin+"/value", []byte("1"), 0644); err != nil {
+	if err := c.backend().WriteFile(c.pinFile("value"), []byte("1"), 0644); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,77 @@
+// Package client implements rpiGpio.Controller against a remote
+// server.Server, so callers can swap a local rpiGpio.Control for a remote
+// one without changing their calling code.
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"rpiGpio/server/gpiopb"
+)
+
+// RemoteControl drives a single pin on a remote GpioService server.
+type RemoteControl struct {
+	pin    string
+	delay  string
+	client gpiopb.GpioServiceClient
+	conn   *grpc.ClientConn
+}
+
+// Dial connects to a GpioService server at addr and returns a RemoteControl
+// for pin.
+func Dial(addr, pin string) (*RemoteControl, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't dial %v: %v", addr, err)
+	}
+	return &RemoteControl{
+		pin:    pin,
+		client: gpiopb.NewGpioServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// Close tears down the underlying gRPC connection.
+func (r *RemoteControl) Close() error {
+	return r.conn.Close()
+}
+
+// SetDelay sets the delay StartTimer asks the server to use.
+func (r *RemoteControl) SetDelay(d string) {
+	r.delay = d
+}
+
+// StartTimer enables the pin then disables it again after delay. ch exists
+// only to satisfy rpiGpio.Controller and isn't used.
+func (r *RemoteControl) StartTimer(ch chan string) error {
+	_, err := r.client.StartTimer(context.Background(), &gpiopb.StartTimerRequest{Pin: r.pin, Delay: r.delay})
+	return err
+}
+
+// Toggle flips the pin's output value. ch exists only to satisfy
+// rpiGpio.Controller and isn't used.
+func (r *RemoteControl) Toggle(ch chan string) error {
+	_, err := r.client.Toggle(context.Background(), &gpiopb.PinRequest{Pin: r.pin})
+	return err
+}
+
+// Watch streams edge events from the server and invokes cb for each one.
+func (r *RemoteControl) Watch(edge string, cb func(pin string)) error {
+	stream, err := r.client.Watch(context.Background(), &gpiopb.WatchRequest{Pin: r.pin, Edge: edge})
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			cb(ev.Pin)
+		}
+	}()
+	return nil
+}
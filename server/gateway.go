@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"rpiGpio/server/gpiopb"
+)
+
+// Gateway exposes a Server's unary RPCs (Toggle, StartTimer and GetValue)
+// over plain HTTP/JSON, for callers that would rather not pull in a gRPC
+// client. The streaming Watch RPC is not served.
+type Gateway struct {
+	s *Server
+}
+
+// NewGateway wraps s for HTTP/JSON access.
+func NewGateway(s *Server) *Gateway {
+	return &Gateway{s: s}
+}
+
+// Handler returns the http.Handler serving the gateway's endpoints.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/toggle", g.handleToggle)
+	mux.HandleFunc("/start-timer", g.handleStartTimer)
+	mux.HandleFunc("/value", g.handleGetValue)
+	return mux
+}
+
+func (g *Gateway) handleToggle(w http.ResponseWriter, r *http.Request) {
+	var in gpiopb.PinRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := g.s.Toggle(context.Background(), &in); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *Gateway) handleStartTimer(w http.ResponseWriter, r *http.Request) {
+	var in gpiopb.StartTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := g.s.StartTimer(context.Background(), &in); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *Gateway) handleGetValue(w http.ResponseWriter, r *http.Request) {
+	var in gpiopb.PinRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	out, err := g.s.GetValue(context.Background(), &in)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(out)
+}
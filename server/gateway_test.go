@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"rpiGpio"
+	"rpiGpio/server/gpiopb"
+)
+
+// newTestGateway wires a Gateway to a Server pointed at a temp directory
+// laid out like sysfs, so the HTTP/JSON path can be exercised end to end
+// without touching the real /sys/class/gpio/ tree.
+func newTestGateway(t *testing.T) *Gateway {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "gpio18"), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "gpio18", "value"), []byte("0\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	s := NewServer(rpiGpio.SetSysfsRoot(root))
+	return NewGateway(s)
+}
+
+func TestGatewayToggleAndGetValue(t *testing.T) {
+	g := newTestGateway(t)
+	ts := httptest.NewServer(g.Handler())
+	defer ts.Close()
+
+	toggleBody, err := json.Marshal(gpiopb.PinRequest{Pin: "18"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	resp, err := ts.Client().Post(ts.URL+"/toggle", "application/json", bytes.NewReader(toggleBody))
+	if err != nil {
+		t.Fatalf("POST /toggle error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 204 {
+		t.Fatalf("POST /toggle status = %v, want 204", resp.StatusCode)
+	}
+
+	valueBody, err := json.Marshal(gpiopb.PinRequest{Pin: "18"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	resp, err = ts.Client().Post(ts.URL+"/value", "application/json", bytes.NewReader(valueBody))
+	if err != nil {
+		t.Fatalf("POST /value error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatalf("POST /value status = %v, want 200", resp.StatusCode)
+	}
+
+	var out gpiopb.ValueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if out.Value != "1" {
+		t.Fatalf("value = %q, want %q after toggling from 0", out.Value, "1")
+	}
+}
+
+func TestGatewayRejectsMalformedJSON(t *testing.T) {
+	g := newTestGateway(t)
+	ts := httptest.NewServer(g.Handler())
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/toggle", "application/json", bytes.NewReader([]byte("{not json")))
+	if err != nil {
+		t.Fatalf("POST /toggle error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Fatalf("POST /toggle with malformed body status = %v, want 400", resp.StatusCode)
+	}
+}
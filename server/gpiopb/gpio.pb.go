@@ -0,0 +1,63 @@
+// Package gpiopb contains the message types for GpioService, declared in
+// gpio.proto. They're hand-written rather than protoc-generated (this repo
+// doesn't wire up protoc/buf codegen), so keep them in sync with
+// gpio.proto by hand when the service changes.
+package gpiopb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// PinRequest identifies the pin an RPC applies to.
+type PinRequest struct {
+	Pin string `protobuf:"bytes,1,opt,name=pin,proto3" json:"pin,omitempty"`
+}
+
+func (m *PinRequest) Reset()         { *m = PinRequest{} }
+func (m *PinRequest) String() string { return proto.CompactTextString(m) }
+func (*PinRequest) ProtoMessage()    {}
+
+// StartTimerRequest is the payload for GpioService.StartTimer.
+type StartTimerRequest struct {
+	Pin   string `protobuf:"bytes,1,opt,name=pin,proto3" json:"pin,omitempty"`
+	Delay string `protobuf:"bytes,2,opt,name=delay,proto3" json:"delay,omitempty"`
+}
+
+func (m *StartTimerRequest) Reset()         { *m = StartTimerRequest{} }
+func (m *StartTimerRequest) String() string { return proto.CompactTextString(m) }
+func (*StartTimerRequest) ProtoMessage()    {}
+
+// WatchRequest is the payload for GpioService.Watch.
+type WatchRequest struct {
+	Pin  string `protobuf:"bytes,1,opt,name=pin,proto3" json:"pin,omitempty"`
+	Edge string `protobuf:"bytes,2,opt,name=edge,proto3" json:"edge,omitempty"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+
+// PinEvent is streamed back from GpioService.Watch for a detected edge.
+type PinEvent struct {
+	Pin string `protobuf:"bytes,1,opt,name=pin,proto3" json:"pin,omitempty"`
+}
+
+func (m *PinEvent) Reset()         { *m = PinEvent{} }
+func (m *PinEvent) String() string { return proto.CompactTextString(m) }
+func (*PinEvent) ProtoMessage()    {}
+
+// ValueResponse is the result of GpioService.GetValue.
+type ValueResponse struct {
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *ValueResponse) Reset()         { *m = ValueResponse{} }
+func (m *ValueResponse) String() string { return proto.CompactTextString(m) }
+func (*ValueResponse) ProtoMessage()    {}
+
+// Empty is returned by RPCs that have nothing to report.
+type Empty struct{}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
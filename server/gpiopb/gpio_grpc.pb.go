@@ -0,0 +1,183 @@
+// Hand-written client/server plumbing for gpio.proto's GpioService (this
+// repo doesn't wire up protoc-gen-go-grpc codegen); keep in sync with
+// gpio.proto by hand when the service changes.
+package gpiopb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// GpioServiceClient is the client API for GpioService.
+type GpioServiceClient interface {
+	Toggle(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*Empty, error)
+	StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*Empty, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (GpioService_WatchClient, error)
+	GetValue(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*ValueResponse, error)
+}
+
+type gpioServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewGpioServiceClient builds a GpioServiceClient on top of an existing
+// *grpc.ClientConn.
+func NewGpioServiceClient(cc grpc.ClientConnInterface) GpioServiceClient {
+	return &gpioServiceClient{cc}
+}
+
+func (c *gpioServiceClient) Toggle(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/gpio.GpioService/Toggle", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gpioServiceClient) StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/gpio.GpioService/StartTimer", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gpioServiceClient) GetValue(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*ValueResponse, error) {
+	out := new(ValueResponse)
+	if err := c.cc.Invoke(ctx, "/gpio.GpioService/GetValue", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gpioServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (GpioService_WatchClient, error) {
+	stream, err := c.cc.(grpc.ClientConnInterface).NewStream(ctx, &_GpioService_serviceDesc.Streams[0], "/gpio.GpioService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gpioServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// GpioService_WatchClient is returned by the Watch streaming RPC.
+type GpioService_WatchClient interface {
+	Recv() (*PinEvent, error)
+	grpc.ClientStream
+}
+
+type gpioServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *gpioServiceWatchClient) Recv() (*PinEvent, error) {
+	m := new(PinEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GpioServiceServer is the server API for GpioService.
+type GpioServiceServer interface {
+	Toggle(context.Context, *PinRequest) (*Empty, error)
+	StartTimer(context.Context, *StartTimerRequest) (*Empty, error)
+	Watch(*WatchRequest, GpioService_WatchServer) error
+	GetValue(context.Context, *PinRequest) (*ValueResponse, error)
+}
+
+// GpioService_WatchServer is used by the server to stream PinEvents back.
+type GpioService_WatchServer interface {
+	Send(*PinEvent) error
+	grpc.ServerStream
+}
+
+type gpioServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *gpioServiceWatchServer) Send(m *PinEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RegisterGpioServiceServer registers srv with s.
+func RegisterGpioServiceServer(s grpc.ServiceRegistrar, srv GpioServiceServer) {
+	s.RegisterService(&_GpioService_serviceDesc, srv)
+}
+
+func _GpioService_Toggle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GpioServiceServer).Toggle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/gpio.GpioService/Toggle"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GpioServiceServer).Toggle(ctx, req.(*PinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GpioService_StartTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GpioServiceServer).StartTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/gpio.GpioService/StartTimer"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GpioServiceServer).StartTimer(ctx, req.(*StartTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GpioService_GetValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GpioServiceServer).GetValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/gpio.GpioService/GetValue"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GpioServiceServer).GetValue(ctx, req.(*PinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GpioService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GpioServiceServer).Watch(m, &gpioServiceWatchServer{stream})
+}
+
+var _GpioService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gpio.GpioService",
+	HandlerType: (*GpioServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Toggle", Handler: _GpioService_Toggle_Handler},
+		{MethodName: "StartTimer", Handler: _GpioService_StartTimer_Handler},
+		{MethodName: "GetValue", Handler: _GpioService_GetValue_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _GpioService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gpio.proto",
+}
@@ -0,0 +1,115 @@
+// Package server wraps rpiGpio.Control and serves it over gRPC (and a thin
+// HTTP/JSON gateway) so a remote client can drive pins on a headless Pi.
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"rpiGpio"
+	"rpiGpio/server/gpiopb"
+)
+
+// Server wraps one rpiGpio.Control per pin, created lazily on first use, so
+// a single process can drive several pins at once.
+type Server struct {
+	cache *rpiGpio.ControlCache
+}
+
+// NewServer creates a Server. opts are applied to every pin session the
+// first time that pin is used, e.g. to share a SetSysfsRoot or SetBackend
+// across all pins.
+func NewServer(opts ...func(*rpiGpio.Control) error) *Server {
+	return &Server{cache: rpiGpio.NewControlCache(opts...)}
+}
+
+// session returns the Control for pin, creating it on first use. extra is
+// (re-)applied to the Control on every call, cached or not, so a later call
+// can still change e.g. its control type or delay instead of silently
+// running against stale settings from whichever call created the session.
+func (s *Server) session(pin string, extra ...func(*rpiGpio.Control) error) (*rpiGpio.Control, error) {
+	return s.cache.Get(pin, []func(*rpiGpio.Control) error{rpiGpio.SetPin(pin)}, extra...)
+}
+
+// Toggle implements gpiopb.GpioServiceServer.
+func (s *Server) Toggle(ctx context.Context, in *gpiopb.PinRequest) (*gpiopb.Empty, error) {
+	ctrl, err := s.session(in.Pin, rpiGpio.SetType("toggle"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ctrl.Run(); err != nil {
+		return nil, err
+	}
+	return &gpiopb.Empty{}, nil
+}
+
+// StartTimer implements gpiopb.GpioServiceServer.
+func (s *Server) StartTimer(ctx context.Context, in *gpiopb.StartTimerRequest) (*gpiopb.Empty, error) {
+	ctrl, err := s.session(in.Pin, rpiGpio.SetType("timer"), rpiGpio.SetDelay(in.Delay))
+	if err != nil {
+		return nil, err
+	}
+	if err := ctrl.Run(); err != nil {
+		return nil, err
+	}
+	return &gpiopb.Empty{}, nil
+}
+
+// GetValue implements gpiopb.GpioServiceServer.
+func (s *Server) GetValue(ctx context.Context, in *gpiopb.PinRequest) (*gpiopb.ValueResponse, error) {
+	ctrl, err := s.session(in.Pin)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ctrl.Value()
+	if err != nil {
+		return nil, err
+	}
+	return &gpiopb.ValueResponse{Value: v}, nil
+}
+
+// Watch implements gpiopb.GpioServiceServer, streaming a PinEvent for edges
+// detected on the pin until the client disconnects. At most one event waits
+// to be sent; an edge that fires while one is already waiting is dropped.
+func (s *Server) Watch(in *gpiopb.WatchRequest, stream gpiopb.GpioService_WatchServer) error {
+	ctrl, err := s.session(in.Pin, rpiGpio.SetDirection("in"))
+	if err != nil {
+		return err
+	}
+
+	events := make(chan string, 1)
+	if err := ctrl.Watch(in.Edge, func(pin string) {
+		select {
+		case events <- pin:
+		default:
+		}
+	}); err != nil {
+		return err
+	}
+	defer ctrl.StopWatch()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case pin := <-events:
+			if err := stream.Send(&gpiopb.PinEvent{Pin: pin}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Serve starts a gRPC listener on addr and blocks until it stops or errors.
+func (s *Server) Serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("couldn't listen on %v: %v", addr, err)
+	}
+	gs := grpc.NewServer()
+	gpiopb.RegisterGpioServiceServer(gs, s)
+	return gs.Serve(lis)
+}
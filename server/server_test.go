@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"rpiGpio"
+)
+
+func TestSessionReusesControlAndReappliesOpts(t *testing.T) {
+	s := NewServer()
+
+	var calls int
+	countOpt := func(c *rpiGpio.Control) error {
+		calls++
+		return nil
+	}
+
+	first, err := s.session("18", countOpt)
+	if err != nil {
+		t.Fatalf("session() error = %v", err)
+	}
+	second, err := s.session("18", countOpt)
+	if err != nil {
+		t.Fatalf("session() error = %v", err)
+	}
+
+	if first != second {
+		t.Fatalf("session() = %p, want cached %p", second, first)
+	}
+	if calls != 2 {
+		t.Fatalf("extra opts called %d times, want 2", calls)
+	}
+}
+
+func TestSessionDistinctPins(t *testing.T) {
+	s := NewServer()
+
+	a, err := s.session("18")
+	if err != nil {
+		t.Fatalf("session(18) error = %v", err)
+	}
+	b, err := s.session("27")
+	if err != nil {
+		t.Fatalf("session(27) error = %v", err)
+	}
+
+	if a == b {
+		t.Fatalf("session() returned the same Control for different pins")
+	}
+}
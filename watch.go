@@ -0,0 +1,231 @@
+package rpiGpio
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// MaxGPIOInterrupt bounds how many pins can be watched concurrently, since
+// each watched pin consumes one slot in the shared epoll instance.
+const MaxGPIOInterrupt = 64
+
+// epollKind tells the shared epoll loop how to drain a ready fd.
+type epollKind int
+
+const (
+	// epollKindLevel is a sysfs value file: a level-style attribute where
+	// only the latest state matters, so one fixed-size read per wakeup is
+	// enough.
+	epollKindLevel epollKind = iota
+	// epollKindQueue is a cdev line-event fd: a genuine kernel event queue
+	// of gpioevent_data records, so a single wakeup can have more than one
+	// record queued up and all of them must be drained before the next
+	// EPOLLET wakeup arrives.
+	epollKindQueue
+)
+
+// gpioeventDataSize is sizeof(struct gpioevent_data) from
+// include/uapi/linux/gpio.h: a u64 timestamp followed by a u32 id (padded).
+const gpioeventDataSize = 16
+
+// gpioEpoll wraps a single epoll instance shared by every watched pin so the
+// driver only needs one background goroutine regardless of how many pins are
+// being watched.
+type gpioEpoll struct {
+	mu        sync.Mutex
+	fd        int
+	callbacks map[int32]func(pin string)
+	pins      map[int32]string
+	files     map[int32]*os.File
+	kinds     map[int32]epollKind
+	started   bool
+}
+
+var (
+	epoller     *gpioEpoll
+	epollerOnce sync.Once
+	epollerErr  error
+)
+
+// getEpoller returns the package-level epoll instance, creating it on first
+// use. sync.Once guards the creation so two goroutines calling Watch/
+// cdevWatch for the first time concurrently (e.g. two server.Server
+// sessions on different pins) can't race on epoller itself.
+func getEpoller() (*gpioEpoll, error) {
+	epollerOnce.Do(func() {
+		fd, err := syscall.EpollCreate1(0)
+		if err != nil {
+			epollerErr = fmt.Errorf("couldn't create epoll instance: %v", err)
+			return
+		}
+		epoller = &gpioEpoll{
+			fd:        fd,
+			callbacks: make(map[int32]func(pin string)),
+			pins:      make(map[int32]string),
+			files:     make(map[int32]*os.File),
+			kinds:     make(map[int32]epollKind),
+		}
+	})
+	return epoller, epollerErr
+}
+
+func (e *gpioEpoll) add(f *os.File, pin string, cb func(pin string), kind epollKind) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.callbacks) >= MaxGPIOInterrupt {
+		return fmt.Errorf("can't watch pin %v, already watching the maximum of %v pins", pin, MaxGPIOInterrupt)
+	}
+
+	fd := int32(f.Fd())
+	flags := int32(syscall.EPOLLIN | syscall.EPOLLPRI | syscall.EPOLLET)
+	event := syscall.EpollEvent{
+		Events: uint32(flags),
+		Fd:     fd,
+	}
+	if err := syscall.EpollCtl(e.fd, syscall.EPOLL_CTL_ADD, int(fd), &event); err != nil {
+		return fmt.Errorf("couldn't register pin %v with epoll: %v", pin, err)
+	}
+
+	e.callbacks[fd] = cb
+	e.pins[fd] = pin
+	e.files[fd] = f
+	e.kinds[fd] = kind
+
+	if !e.started {
+		e.started = true
+		go e.loop()
+	}
+	return nil
+}
+
+func (e *gpioEpoll) remove(f *os.File) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fd := int32(f.Fd())
+	syscall.EpollCtl(e.fd, syscall.EPOLL_CTL_DEL, int(fd), nil)
+	delete(e.callbacks, fd)
+	delete(e.pins, fd)
+	delete(e.files, fd)
+	delete(e.kinds, fd)
+}
+
+func (e *gpioEpoll) loop() {
+	events := make([]syscall.EpollEvent, MaxGPIOInterrupt)
+	for {
+		n, err := syscall.EpollWait(e.fd, events, -1)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return
+		}
+		for i := 0; i < n; i++ {
+			fd := events[i].Fd
+			e.mu.Lock()
+			cb, ok := e.callbacks[fd]
+			pin := e.pins[fd]
+			f := e.files[fd]
+			kind := e.kinds[fd]
+			e.mu.Unlock()
+			if !ok {
+				continue
+			}
+
+			if kind == epollKindQueue {
+				// EPOLLET only wakes us once per readability transition, but
+				// the event fd is a real queue: drain every gpioevent_data
+				// record that's ready now, or edges that bounced in between
+				// wakeups would sit unread with no further notification.
+				buf := make([]byte, gpioeventDataSize)
+				for {
+					n, err := syscall.Read(int(fd), buf)
+					if n <= 0 || err != nil {
+						break
+					}
+					cb(pin)
+				}
+				continue
+			}
+
+			// the value file has to be seeked back to 0 and re-read so the
+			// next edge keeps triggering EPOLLPRI.
+			if f != nil {
+				f.Seek(0, os.SEEK_SET)
+				buf := make([]byte, 16)
+				f.Read(buf)
+			}
+			cb(pin)
+		}
+	}
+}
+
+// Watch configures edge detection on the pin (one of "rising", "falling",
+// "both" or "none") and invokes cb whenever the pin's value changes, without
+// polling. The callback runs on the shared epoll goroutine, so it should
+// return quickly.
+func (c *Control) Watch(edge string, cb func(pin string)) error {
+	switch edge {
+	case "rising", "falling", "both", "none":
+	default:
+		return fmt.Errorf("invalid edge type:%v, choose one of :[rising falling both none]", edge)
+	}
+
+	if edge == "none" {
+		return c.StopWatch()
+	}
+
+	if c.backendType == "cdev" {
+		return c.cdevWatch(edge, cb)
+	}
+
+	if err := c.enablePin(); err != nil {
+		return fmt.Errorf("couldn't enable pin %v, because %v", c.pin, err)
+	}
+
+	if err := c.backend().WriteFile(c.pinFile("edge"), []byte(edge), 0644); err != nil {
+		return fmt.Errorf("couldn't set edge for pin %v: %v", c.pin, err)
+	}
+
+	f, err := os.OpenFile(c.pinFile("value"), os.O_RDONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("couldn't open value file for pin %v: %v", c.pin, err)
+	}
+
+	e, err := getEpoller()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := e.add(f, c.pin, cb, epollKindLevel); err != nil {
+		f.Close()
+		return err
+	}
+
+	c.watchFile = f
+	return nil
+}
+
+// StopWatch unregisters the pin from the shared epoll instance, disables
+// edge detection and releases the value file opened by Watch.
+func (c *Control) StopWatch() error {
+	if c.watchFile == nil {
+		return nil
+	}
+
+	if epoller != nil {
+		epoller.remove(c.watchFile)
+	}
+
+	var err error
+	if c.backendType != "cdev" {
+		err = c.backend().WriteFile(c.pinFile("edge"), []byte("none"), 0644)
+	}
+	c.watchFile.Close()
+	c.watchFile = nil
+	return err
+}
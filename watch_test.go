@@ -0,0 +1,98 @@
+package rpiGpio
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWatchInvalidEdge(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio18"] = nil
+
+	ctrl := newTestControl(t, fs, SetDirection("in"))
+	if err := ctrl.Watch("sideways", func(string) {}); err == nil {
+		t.Fatalf("Watch() error = nil, want an error for an invalid edge")
+	}
+
+	if _, err := fs.ReadFile("/fake/gpio/gpio18/edge"); err == nil {
+		t.Fatalf("edge file was written for an invalid edge request")
+	}
+}
+
+// The epoll registration itself can't be faked (it needs a real fd of a
+// kind epoll supports), so this only exercises the part of Watch that goes
+// through fsBackend: enabling the pin and writing the edge file.
+func TestWatchWritesEdgeFile(t *testing.T) {
+	fs := newFakeFS()
+	fs.files["/fake/gpio/gpio18"] = nil
+
+	ctrl := newTestControl(t, fs, SetDirection("in"))
+	err := ctrl.Watch("rising", func(string) {})
+	if err == nil {
+		t.Fatalf("Watch() error = nil, want an error opening the fake value file")
+	}
+
+	got, readErr := fs.ReadFile("/fake/gpio/gpio18/edge")
+	if readErr != nil {
+		t.Fatalf("edge file was never written: %v", readErr)
+	}
+	if string(got) != "rising" {
+		t.Fatalf("edge file = %q, want %q", got, "rising")
+	}
+}
+
+// TestGetEpollerConcurrent exercises the sync.Once guard around the
+// package-level epoller: run with -race, concurrent first calls must not
+// race on creating it (e.g. two server.Server sessions calling Watch for
+// different pins at the same time).
+func TestGetEpollerConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	results := make([]*gpioEpoll, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e, err := getEpoller()
+			if err != nil {
+				t.Errorf("getEpoller() error = %v", err)
+				return
+			}
+			results[i] = e
+		}(i)
+	}
+	wg.Wait()
+
+	for i, e := range results {
+		if e != results[0] {
+			t.Fatalf("getEpoller() call %d = %p, want the same instance as call 0 (%p)", i, e, results[0])
+		}
+	}
+}
+
+// TestWatchNoneDelegatesToStopWatch mirrors cdevWatch's "none" short
+// circuit: Watch("none", ...) must not enable the pin, write the edge file,
+// or register a value-file fd that would never fire.
+func TestWatchNoneDelegatesToStopWatch(t *testing.T) {
+	fs := newFakeFS()
+	ctrl := newTestControl(t, fs, SetDirection("in"))
+
+	if err := ctrl.Watch("none", func(string) {}); err != nil {
+		t.Fatalf("Watch(\"none\") error = %v", err)
+	}
+
+	if _, err := fs.ReadFile("/fake/gpio/gpio18/edge"); err == nil {
+		t.Fatalf("edge file was written for Watch(\"none\")")
+	}
+	if ctrl.watchFile != nil {
+		t.Fatalf("watchFile = %v, want nil after Watch(\"none\")", ctrl.watchFile)
+	}
+}
+
+func TestStopWatchWithoutWatchIsNoop(t *testing.T) {
+	fs := newFakeFS()
+	ctrl := newTestControl(t, fs)
+
+	if err := ctrl.StopWatch(); err != nil {
+		t.Fatalf("StopWatch() error = %v, want nil when nothing is being watched", err)
+	}
+}